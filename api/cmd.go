@@ -63,6 +63,14 @@ func ClearPageCache(ctx *gin.Context) {
 	library.ClearPageCache()
 }
 
+// CheckBurst triggers check for installed and enabled providers
+func CheckBurst(ctx *gin.Context) {
+	if ctx != nil {
+		ctx.Abort()
+	}
+	go config.CheckBurst()
+}
+
 // ClearTraktCache ...
 func ClearTraktCache(ctx *gin.Context) {
 	if ctx != nil {
@@ -71,6 +71,7 @@ func Status(ctx *gin.Context) {
 [B]LOCALIZE[30395]:[/B] %s
 [B]LOCALIZE[30396]:[/B] %d
 [B]LOCALIZE[30488]:[/B] %d
+[B]LOCALIZE[30669]:[/B] %s
 
 [COLOR pink][B]LOCALIZE[30399]:[/B][/COLOR]
     [B]LOCALIZE[30397]:[/B] %s
@@ -93,6 +94,11 @@ func Status(ctx *gin.Context) {
 		ip = localIP.String()
 	}
 
+	dnsServer := proxy.BestOpennicServer()
+	if dnsServer == "" {
+		dnsServer = "-"
+	}
+
 	port := config.Args.LocalPort
 	webAddress := fmt.Sprintf("http://%s:%d/web", ip, port)
 	debugAllAddress := fmt.Sprintf("http://%s:%d/debug/all", ip, port)
@@ -112,6 +118,7 @@ func Status(ctx *gin.Context) {
 		ip,
 		port,
 		proxy.ProxyPort,
+		dnsServer,
 
 		webAddress,
 		infoAddress,
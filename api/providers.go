@@ -3,7 +3,6 @@ package api
 import (
 	"fmt"
 	"sort"
-	"strings"
 
 	"github.com/anacrolix/missinggo/perf"
 	"github.com/gin-gonic/gin"
@@ -16,11 +15,9 @@ const providerPrefix = "plugin://plugin.video.elementum/provider/"
 
 // Addon ...
 type Addon struct {
-	ID      string
-	Name    string
-	Version string
-	Enabled bool
+	config.Addon
 	Status  int
+	Allowed bool
 }
 
 // ByEnabled ...
@@ -41,16 +38,12 @@ func getProviders() []Addon {
 	defer perf.ScopeTimer()()
 
 	list := make([]Addon, 0)
-	for _, addon := range xbmc.GetAddons("xbmc.python.script", "executable", "all", []string{"name", "version", "enabled"}).Addons {
-		if strings.HasPrefix(addon.ID, "script.elementum.") {
-			list = append(list, Addon{
-				ID:      addon.ID,
-				Name:    addon.Name,
-				Version: addon.Version,
-				Enabled: addon.Enabled,
-				Status:  xbmc.AddonCheck(addon.ID),
-			})
-		}
+	for _, addon := range config.GetProviderAddons() {
+		list = append(list, Addon{
+			Addon:   addon,
+			Status:  xbmc.AddonCheck(addon.ID),
+			Allowed: config.IsProviderAllowed(addon.ID),
+		})
 	}
 	sort.Sort(ByStatus(list))
 	sort.Sort(ByEnabled(list))
@@ -73,6 +66,15 @@ func ProviderList(ctx *gin.Context) {
 			enabled = "[COLOR FF990000]Disabled[/COLOR]"
 		}
 
+		// Allowlist status is shown only if allowlist is used
+		if len(config.Get().ProviderAllowlist) > 0 {
+			if provider.Allowed {
+				enabled += " - [COLOR FF009900]Allowed[/COLOR]"
+			} else {
+				enabled += " - [COLOR FF999900]Not allowed[/COLOR]"
+			}
+		}
+
 		item := &xbmc.ListItem{
 			Label:      fmt.Sprintf("%s - %s - %s %s", status, enabled, provider.Name, provider.Version),
 			Path:       URLForXBMC("/provider/%s/settings", provider.ID),
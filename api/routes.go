@@ -376,6 +376,7 @@ func Routes(s *bittorrent.Service) *gin.Engine {
 		cmd.GET("/clear_page_cache", ClearPageCache)
 		cmd.GET("/clear_trakt_cache", ClearTraktCache)
 		cmd.GET("/clear_tmdb_cache", ClearTmdbCache)
+		cmd.GET("/check_burst", CheckBurst)
 
 		cmd.GET("/reset_path", ResetPath)
 		cmd.GET("/reset_path/:path", ResetCustomPath)
@@ -32,6 +32,9 @@ func DebugAll(s *Service) http.Handler {
 
 		writeHeader(w, "Debug Vars")
 		writeResponse(w, "/debug/vars")
+
+		writeHeader(w, "Configuration Reload")
+		writeReloadResult(w)
 	})
 }
 
@@ -65,6 +68,9 @@ func DebugBundle(s *Service) http.Handler {
 		writeHeader(w, "Debug Vars")
 		writeResponse(w, "/debug/vars")
 
+		writeHeader(w, "Configuration Reload")
+		writeReloadResult(w)
+
 		writeHeader(w, "kodi.log")
 		io.Copy(w, logFile)
 	})
@@ -87,3 +93,22 @@ func writeResponse(w http.ResponseWriter, url string) {
 
 	io.Copy(w, resp.Body)
 }
+
+func writeReloadResult(w http.ResponseWriter) {
+	result := config.LastReloadResult()
+	if result == nil {
+		w.Write([]byte("Configuration was not reloaded yet\n"))
+		return
+	}
+
+	fmt.Fprintf(w, "Duration: %s\n", result.Duration)
+	if result.Changed != nil {
+		fmt.Fprintf(w, "Changed: %s\n", strings.Join(result.Changed.Fields, ", "))
+	}
+	for _, err := range result.Errors {
+		fmt.Fprintf(w, "Error: %s\n", err)
+	}
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(w, "Warning: %s\n", warning)
+	}
+}
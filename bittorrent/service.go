@@ -12,7 +12,6 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
-	"runtime"
 	"sort"
 	"strconv"
 	"strings"
@@ -159,10 +158,16 @@ func (s *Service) CloseSession() {
 // and Kodi sent a notification about that.
 // Should reassemble Service configuration and restart everything.
 // For non-memory storage it should also load old torrent files.
-func (s *Service) Reconfigure() {
+// If new configuration cannot be applied, services are restarted with previous one.
+// Returns the result of configuration reload.
+func (s *Service) Reconfigure() (*config.ReloadResult, error) {
 	s.stopServices()
 
-	config.Reload()
+	result, err := config.ReloadWithResult()
+	if err != nil {
+		log.Errorf("Could not reload configuration, keeping previous one: %s", err)
+		go xbmc.Dialog("Elementum", err.Error())
+	}
 	proxy.Reload()
 	UpdateDefaultTrackers()
 
@@ -175,6 +180,8 @@ func (s *Service) Reconfigure() {
 	if config.Get().TraktToken != "" && !config.Get().TraktAuthorized {
 		trakt.GetLastActivities()
 	}
+
+	return result, err
 }
 
 func (s *Service) configure() {
@@ -225,7 +232,11 @@ func (s *Service) configure() {
 	// settings.SetInt("torrent_connect_boost", 20)
 	// settings.SetInt("torrent_connect_boost", 100)
 	// settings.SetInt("torrent_connect_boost", 0)
-	settings.SetInt("aio_threads", runtime.NumCPU()*4)
+	// Libtorrent uses each 4th disk thread for hashing pieces,
+	// so we scale disk threads to get configured number of hashing threads.
+	// By default it is one hashing thread per CPU, or a single one on low-power devices.
+	log.Infof("Using %d threads for pieces hashing", s.config.HashingThreads)
+	settings.SetInt("aio_threads", s.config.HashingThreads*4)
 	settings.SetInt("cache_size", -1)
 	settings.SetInt("mixed_mode_algorithm", int(lt.SettingsPackPreferTcp))
 
@@ -449,8 +460,10 @@ func (s *Service) configure() {
 	rand.Seed(time.Now().UTC().UnixNano())
 
 	listenInterfaces := []string{"0.0.0.0"}
-	if !s.config.ListenAutoDetectIP && strings.TrimSpace(s.config.ListenInterfaces) != "" {
-		listenInterfaces = strings.Split(strings.Replace(strings.TrimSpace(s.config.ListenInterfaces), " ", "", -1), ",")
+	if !s.config.ListenAutoDetectIP && len(s.config.ListenInterfacesList) > 0 {
+		listenInterfaces = s.config.ListenInterfacesList
+	} else if !s.config.ListenAutoDetectIP && strings.TrimSpace(s.config.ListenInterfaces) != "" {
+		log.Warningf("None of listen interfaces '%s' can be used, listening on all interfaces", s.config.ListenInterfaces)
 	}
 
 	s.mappedPorts = map[string]int{}
@@ -830,6 +843,10 @@ func (s *Service) AddTorrent(uri string, paused bool, downloadStorage int, first
 
 	if !t.HasMetadata() {
 		if err := t.WaitForMetadata(infoHash); err != nil {
+			// Aborting magnet resolution to avoid hanging torrent in the session
+			xbmc.Notify("Elementum", "LOCALIZE[30670]", config.AddonIcon())
+			s.q.Delete(t)
+			t.Drop(true, true)
 			return nil, err
 		}
 	}
@@ -907,6 +924,56 @@ func (s *Service) RemoveTorrent(t *Torrent, forceDrop, forceDelete, isWatched bo
 	return true
 }
 
+// moveCompletedTorrent moves whole torrent content to destination path,
+// keeping torrent's internal folder structure.
+func (s *Service) moveCompletedTorrent(infoHash string, torrentName string, torrentInfo lt.TorrentInfo, dstPath string) error {
+	if torrentInfo == nil || torrentInfo.Swigcptr() == 0 || torrentInfo.NumFiles() <= 0 {
+		return fmt.Errorf("No torrent info to move %s", torrentName)
+	}
+
+	// All files of multi-file torrent are located in a single root folder,
+	// for single-file torrent the root is the file itself.
+	firstPath := filepath.ToSlash(torrentInfo.Files().FilePath(0))
+	rootPath := strings.SplitN(firstPath, "/", 2)[0]
+	srcPath := filepath.Join(s.config.DownloadPath, rootPath)
+
+	if _, err := os.Stat(srcPath); err != nil {
+		return err
+	}
+	if config.IsSubPath(srcPath, dstPath) {
+		return fmt.Errorf("Cannot move %s into itself: %s", srcPath, dstPath)
+	}
+
+	files := []string{}
+	filepath.Walk(srcPath, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+
+	go func() {
+		log.Infof("Moving torrent %s from %s to %s", torrentName, srcPath, dstPath)
+		dst, err := util.Move(srcPath, dstPath)
+		if err != nil {
+			log.Error(err)
+			return
+		}
+
+		for _, f := range files {
+			if rel, err := filepath.Rel(srcPath, f); err == nil {
+				log.Infof("Moved %s to %s", f, filepath.Join(dst, rel))
+			}
+		}
+		log.Warningf("%s moved to %s, %d file(s) in total", torrentName, dst, len(files))
+
+		log.Infof("Marking %s for removal from library and database...", torrentName)
+		database.GetStorm().UpdateBTItemStatus(infoHash, Remove)
+	}()
+
+	return nil
+}
+
 func (s *Service) onStateChanged(stateAlert lt.StateChangedAlert) {
 	switch stateAlert.GetState() {
 	case lt.TorrentStatusDownloading:
@@ -1435,7 +1502,30 @@ func (s *Service) downloadProgress() {
 						return errors.New("No files saved for BTItem")
 					}
 
+					var dstPath string
+					if item.Type == "movie" {
+						dstPath = filepath.Dir(s.config.CompletedMoviesPath)
+					} else {
+						dstPath = filepath.Dir(s.config.CompletedShowsPath)
+						if item.ShowID > 0 {
+							show := tmdb.GetShow(item.ShowID, config.Get().Language)
+							if show != nil {
+								showPath := util.ToFileName(fmt.Sprintf("%s (%s)", show.Name, strings.Split(show.FirstAirDate, "-")[0]))
+								seasonPath := filepath.Join(showPath, fmt.Sprintf("Season %d", item.Season))
+								if item.Season == 0 {
+									seasonPath = filepath.Join(showPath, "Specials")
+								}
+								dstPath = filepath.Join(dstPath, seasonPath)
+								os.MkdirAll(dstPath, 0755)
+							}
+						}
+					}
+
 					torrentInfo := torrentHandle.TorrentFile()
+					if s.config.CompletedMoveScope == config.CompletedMoveTorrent {
+						return s.moveCompletedTorrent(infoHash, torrentName, torrentInfo, dstPath)
+					}
+
 					for _, fp := range item.Files {
 						f := t.GetFileByPath(fp)
 
@@ -1469,25 +1559,6 @@ func (s *Service) downloadProgress() {
 							}
 						}
 
-						var dstPath string
-						if item.Type == "movie" {
-							dstPath = filepath.Dir(s.config.CompletedMoviesPath)
-						} else {
-							dstPath = filepath.Dir(s.config.CompletedShowsPath)
-							if item.ShowID > 0 {
-								show := tmdb.GetShow(item.ShowID, config.Get().Language)
-								if show != nil {
-									showPath := util.ToFileName(fmt.Sprintf("%s (%s)", show.Name, strings.Split(show.FirstAirDate, "-")[0]))
-									seasonPath := filepath.Join(showPath, fmt.Sprintf("Season %d", item.Season))
-									if item.Season == 0 {
-										seasonPath = filepath.Join(showPath, "Specials")
-									}
-									dstPath = filepath.Join(dstPath, seasonPath)
-									os.MkdirAll(dstPath, 0755)
-								}
-							}
-						}
-
 						go func() {
 							log.Infof("Moving %s to %s", fileName, dstPath)
 							srcPath := filepath.Join(s.config.DownloadPath, filePath)
@@ -1530,15 +1601,18 @@ func (s *Service) downloadProgress() {
 					showTorrent = fmt.Sprintf("%s - %s - %s", torrentName, humanize.Bytes(uint64(activeTorrents[showNext].downloadRate))+"/s", humanize.Bytes(uint64(activeTorrents[showNext].uploadRate))+"/s")
 					showNext++
 				}
-				if !s.config.DisableBgProgress && (!s.config.DisableBgProgressPlayback || !s.anyPlayerIsPlaying()) {
+				if s.isBgProgressVisible() {
 					if s.dialogProgressBG == nil {
 						s.dialogProgressBG = xbmc.NewDialogProgressBG("Elementum", "")
 					}
 					if s.dialogProgressBG != nil {
 						s.dialogProgressBG.Update(showProgress, "Elementum", showTorrent)
 					}
+				} else if s.dialogProgressBG != nil {
+					s.dialogProgressBG.Close()
+					s.dialogProgressBG = nil
 				}
-			} else if (!s.config.DisableBgProgress || (s.config.DisableBgProgressPlayback && s.anyPlayerIsPlaying())) && s.dialogProgressBG != nil {
+			} else if s.dialogProgressBG != nil {
 				s.dialogProgressBG.Close()
 				s.dialogProgressBG = nil
 			}
@@ -1546,6 +1620,18 @@ func (s *Service) downloadProgress() {
 	}
 }
 
+// isBgProgressVisible checks whether background progress should be shown now
+func (s *Service) isBgProgressVisible() bool {
+	switch s.config.BackgroundProgressMode() {
+	case config.BgModeAlways:
+		return true
+	case config.BgModeHiddenDuringPlayback:
+		return !s.anyPlayerIsPlaying()
+	}
+
+	return false
+}
+
 // SetDownloadLimit ...
 func (s *Service) SetDownloadLimit(i int) {
 	settings := s.PackSettings
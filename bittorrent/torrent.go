@@ -1577,7 +1577,8 @@ func (t *Torrent) WaitForMetadata(infoHash string) (err error) {
 	sc := t.Service.Closer.C()
 	tc := t.Closer.C()
 	mc := t.GotInfo()
-	to := time.NewTicker(time.Duration(config.Get().MagnetResolveTimeout) * time.Second)
+	timeout := config.Get().MetadataFetchTimeout
+	to := time.NewTicker(time.Duration(timeout) * time.Second)
 	defer to.Stop()
 
 	log.Infof("Waiting for information fetched for torrent: %s", infoHash)
@@ -1591,7 +1592,7 @@ func (t *Torrent) WaitForMetadata(infoHash string) (err error) {
 	for {
 		select {
 		case <-to.C:
-			err = fmt.Errorf("Expired timeout for resolving magnet link for %d seconds", config.Get().MagnetResolveTimeout)
+			err = fmt.Errorf("Could not fetch torrent info in %d seconds", timeout)
 			log.Error(err)
 			return err
 
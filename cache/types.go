@@ -55,6 +55,8 @@ const (
 
 	TraktActivitiesKey                     = TraktKey + "last_activities"
 	TraktActivitiesExpire                  = 30 * 24 * time.Hour
+	TraktLastSyncKey                       = TraktKey + "last_sync"
+	TraktLastSyncExpire                    = 30 * 24 * time.Hour
 	TraktPausedLastUpdatesKey              = TraktKey + "PausedLastUpdates.%d"
 	TraktPausedLastUpdatesExpire           = 30 * 24 * time.Hour
 	TraktMovieKey                          = TraktKey + "movie.%s"
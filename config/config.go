@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime"
 	"strconv"
 	"strings"
 	"sync"
@@ -30,8 +31,21 @@ const (
 	maxMemorySize                = 300 * 1024 * 1024
 	defaultAutoMemorySize        = 40 * 1024 * 1024
 	defaultTraktSyncFrequencyMin = 5
+	maxTraktSyncOfflineRetry     = 10
 	defaultEndBufferSize         = 1 * 1024 * 1024
 	defaultDiskCacheSize         = 12 * 1024 * 1024
+	defaultDNSBenchmarkInterval  = 60
+	defaultMetadataFetchTimeout  = 60
+
+	// minXbmcSettingsCount is a number of settings, less than which means Kodi returned incomplete list.
+	// Addon has about 200 settings, while Kodi, that is still initializing, returns just a few of them,
+	// so half of the settings is a safe threshold, that does not need updating for every new setting.
+	minXbmcSettingsCount   = 100
+	xbmcSettingsFetchTries = 4
+
+	defaultMetadataPrefetch         = 8
+	defaultMetadataPrefetchLowPower = 3
+	maxMetadataPrefetch             = 20
 
 	// TraktReadClientID ...
 	TraktReadClientID = "eb8839a79fb2af4ebfb93f993a8a539abd4d9674a7638497bbc662d2a4b22346"
@@ -46,13 +60,16 @@ const (
 // Configuration ...
 type Configuration struct {
 	DownloadPath                string
+	DownloadPathResolved        string
 	TorrentsPath                string
 	LibraryPath                 string
+	LibraryPathResolved         string
 	Info                        *xbmc.AddonInfo
 	Platform                    *xbmc.Platform
 	Language                    string
 	Region                      string
 	TemporaryPath               string
+	TemporaryPathResolved       string
 	ProfilePath                 string
 	HomePath                    string
 	XbmcPath                    string
@@ -144,17 +161,19 @@ type Configuration struct {
 	ListenPortMin            int
 	ListenPortMax            int
 	ListenInterfaces         string
+	ListenInterfacesList     []string
 	ListenAutoDetectIP       bool
 	ListenAutoDetectPort     bool
 	OutgoingInterfaces       string
 	TunedStorage             bool
 	DiskCacheSize            int
+	HashingThreads           int
 	UseLibtorrentConfig      bool
 	UseLibtorrentLogging     bool
 	UseLibtorrentDeadlines   bool
 	UseLibtorrentPauseResume bool
 	LibtorrentProfile        int
-	MagnetResolveTimeout     int
+	MetadataFetchTimeout     int
 	AddExtraTrackers         int
 	RemoveOriginalTrackers   bool
 	ModifyTrackersStrategy   int
@@ -176,6 +195,7 @@ type Configuration struct {
 	TraktSyncEnabled               bool
 	TraktSyncPlaybackEnabled       bool
 	TraktSyncFrequencyMin          int
+	TraktSyncOfflineRetry          int
 	TraktSyncCollections           bool
 	TraktSyncWatchlist             bool
 	TraktSyncUserlists             bool
@@ -219,6 +239,7 @@ type Configuration struct {
 	OSDBUser               string
 	OSDBPass               string
 	OSDBLanguage           string
+	OSDBLanguageList       []string
 	OSDBAutoLanguage       bool
 	OSDBAutoLoad           bool
 	OSDBAutoLoadCount      int
@@ -235,9 +256,15 @@ type Configuration struct {
 
 	CustomProviderTimeoutEnabled bool
 	CustomProviderTimeout        int
+	ProviderAllowlist            []string
+	CheckProvidersOnReload       bool
+
+	MetadataPrefetchConcurrency int
 
-	InternalDNSEnabled  bool
-	InternalDNSSkipIPv6 bool
+	InternalDNSEnabled   bool
+	InternalDNSSkipIPv6  bool
+	DNSBenchmark         bool // selects the fastest Opennic server, used only for Opennic zones
+	DNSBenchmarkInterval int
 
 	InternalProxyEnabled     bool
 	InternalProxyLogging     bool
@@ -255,6 +282,7 @@ type Configuration struct {
 	ProxyUseDownload bool
 
 	CompletedMove       bool
+	CompletedMoveScope  int
 	CompletedMoviesPath string
 	CompletedShowsPath  string
 
@@ -262,6 +290,25 @@ type Configuration struct {
 	LogLevel        int
 }
 
+// BgMode defines when background progress dialog should be shown
+type BgMode int
+
+const (
+	// BgModeOff never shows background progress
+	BgModeOff BgMode = iota
+	// BgModeAlways always shows background progress
+	BgModeAlways
+	// BgModeHiddenDuringPlayback shows background progress only when nothing is playing
+	BgModeHiddenDuringPlayback
+)
+
+const (
+	// CompletedMovePlayed moves only played files
+	CompletedMovePlayed int = iota
+	// CompletedMoveTorrent moves whole torrent, keeping its folder structure
+	CompletedMoveTorrent
+)
+
 // Addon ...
 type Addon struct {
 	ID      string
@@ -273,9 +320,17 @@ type Addon struct {
 type XbmcSettings map[string]interface{}
 
 var (
-	config          = &Configuration{}
-	lock            = sync.RWMutex{}
-	settingsWarning = ""
+	// getAllSettings is a source of addon settings, can be replaced to use other source
+	getAllSettings = xbmc.GetAllSettings
+	// removeAll is used to clean folders, can be replaced to emulate filesystem failures
+	removeAll = os.RemoveAll
+	// xbmcSettingsFetchDelay is the delay before first retry of incomplete settings fetch, doubled on each retry
+	xbmcSettingsFetchDelay = 1 * time.Second
+	// interfaceByName is used to validate network interfaces, can be replaced to emulate other hosts
+	interfaceByName = net.InterfaceByName
+
+	config = &Configuration{}
+	lock   = sync.RWMutex{}
 
 	proxyTypes = []string{
 		"Socks4",
@@ -313,8 +368,38 @@ func Get() *Configuration {
 	return config
 }
 
-// Reload ...
+// Reload is a legacy variant of ReloadWithResult. If configuration cannot be loaded,
+// it opens addon settings window, waits for it to be closed and exits the process,
+// so the daemon is restarted with fixed settings. Returns new configuration.
+// Note that on the first reload there is no previous configuration to keep,
+// so incomplete settings from Kodi are still applied, and usually end up
+// in the settings window and exit, as required paths are empty.
 func Reload() *Configuration {
+	result, err := ReloadWithResult()
+	if err != nil {
+		log.Warningf("Addon settings not properly set, opening settings window: %#v", err)
+
+		message := "LOCALIZE[30314]"
+		if err.Error() != "" {
+			message = err.Error()
+		}
+
+		xbmc.AddonSettings("plugin.video.elementum")
+		xbmc.Dialog("Elementum", message)
+
+		waitForSettingsClosed()
+
+		// Custom code to say python not to report this error
+		os.Exit(5)
+	}
+
+	return result.Config
+}
+
+// reload reads addon settings and stores new configuration.
+// If Kodi returns incomplete settings, previous configuration is kept,
+// unless this is the first reload. Panics if settings are not usable.
+func reload(result *ReloadResult) *Configuration {
 	log.Info("Reloading configuration...")
 
 	// Reloading RPC Hosts
@@ -323,30 +408,22 @@ func Reload() *Configuration {
 	xbmc.XBMCExJSONRPCHosts = []string{net.JoinHostPort(Args.RemoteHost, strconv.Itoa(Args.RemotePort))}
 	xbmc.XBMCExJSONRPCPort = strconv.Itoa(Args.RemotePort)
 
-	defer func() {
-		if r := recover(); r != nil {
-			log.Warningf("Addon settings not properly set, opening settings window: %#v", r)
-
-			message := "LOCALIZE[30314]"
-			if settingsWarning != "" {
-				message = settingsWarning
-			}
-
-			xbmc.AddonSettings("plugin.video.elementum")
-			xbmc.Dialog("Elementum", message)
-
-			waitForSettingsClosed()
-
-			// Custom code to say python not to report this error
-			os.Exit(5)
+	// Kodi can return partial settings list while it is still initializing,
+	// so we should not apply such settings over working configuration.
+	previous := Get()
+	xbmcSettings := fetchXbmcSettings()
+	if len(xbmcSettings) < minXbmcSettingsCount {
+		if previous != nil && previous.Info != nil {
+			result.warn(fmt.Sprintf("Kodi returned only %d settings, keeping previous configuration", len(xbmcSettings)))
+			return previous
 		}
-	}()
+		result.warn(fmt.Sprintf("Kodi returned only %d settings, configuration can be incomplete", len(xbmcSettings)))
+	}
 
 	info := xbmc.GetAddonInfo()
 	if info == nil || info.ID == "" {
 		log.Warningf("Can't continue because addon info is empty")
-		settingsWarning = "LOCALIZE[30113]"
-		panic(settingsWarning)
+		panic("LOCALIZE[30113]")
 	}
 
 	info.Path = xbmc.TranslatePath(info.Path)
@@ -380,10 +457,11 @@ func Reload() *Configuration {
 		}
 	}
 
-	os.RemoveAll(info.TempPath)
+	info.TempPath = prepareTemporaryPath(result, info.TempPath, previous)
 	if err := os.MkdirAll(info.TempPath, 0777); err != nil {
 		log.Infof("Could not create temporary directory: %#v", err)
 	}
+	tempPathResolved := resolvePath(info.TempPath)
 
 	if platform.OS == "android" {
 		legacyPath := strings.Replace(info.Path, "/storage/emulated/0", "/storage/emulated/legacy", 1)
@@ -417,34 +495,33 @@ func Reload() *Configuration {
 
 	log.Noticef("Paths translated by Kodi: Download = %s , Library = %s , Torrents = %s , Storage = %d", downloadPath, libraryPath, torrentsPath, downloadStorage)
 
+	// Symlinks are resolved to validate and compare real locations
+	downloadPathResolved := resolvePath(downloadPath)
+
 	if downloadStorage != 1 {
 		if downloadPath == "." {
 			log.Warningf("Can't continue because download path is empty")
-			settingsWarning = "LOCALIZE[30113]"
-			panic(settingsWarning)
-		} else if err := IsWritablePath(downloadPath); err != nil {
+			panic("LOCALIZE[30113]")
+		} else if err := IsWritablePath(downloadPathResolved); err != nil {
 			log.Errorf("Cannot write to download location '%s': %#v", downloadPath, err)
-			settingsWarning = err.Error()
-			panic(settingsWarning)
+			panic(err)
 		}
 	}
 	log.Infof("Using download path: %s", downloadPath)
 
 	if libraryPath == "." {
 		log.Errorf("Cannot use library location '%s'", libraryPath)
-		settingsWarning = "LOCALIZE[30220]"
-		panic(settingsWarning)
+		panic("LOCALIZE[30220]")
 	} else if strings.Contains(libraryPath, "elementum_library") {
 		if err := os.MkdirAll(libraryPath, 0777); err != nil {
 			log.Errorf("Could not create temporary library directory: %#v", err)
-			settingsWarning = err.Error()
-			panic(settingsWarning)
+			panic(err)
 		}
 	}
-	if err := IsWritablePath(libraryPath); err != nil {
+	libraryPathResolved := resolvePath(libraryPath)
+	if err := IsWritablePath(libraryPathResolved); err != nil {
 		log.Errorf("Cannot write to library location '%s': %#v", libraryPath, err)
-		settingsWarning = err.Error()
-		panic(settingsWarning)
+		panic(err)
 	}
 	log.Infof("Using library path: %s", libraryPath)
 
@@ -453,18 +530,15 @@ func Reload() *Configuration {
 	} else if strings.Contains(torrentsPath, "elementum_torrents") {
 		if err := os.MkdirAll(torrentsPath, 0777); err != nil {
 			log.Errorf("Could not create temporary torrents directory: %#v", err)
-			settingsWarning = err.Error()
-			panic(settingsWarning)
+			panic(err)
 		}
 	}
 	if err := IsWritablePath(torrentsPath); err != nil {
 		log.Errorf("Cannot write to location '%s': %#v", torrentsPath, err)
-		settingsWarning = err.Error()
-		panic(settingsWarning)
+		panic(err)
 	}
 	log.Infof("Using torrents path: %s", torrentsPath)
 
-	xbmcSettings := xbmc.GetAllSettings()
 	settings := XbmcSettings{}
 	for _, setting := range xbmcSettings {
 		switch setting.Type {
@@ -500,13 +574,16 @@ func Reload() *Configuration {
 
 	newConfig := Configuration{
 		DownloadPath:                downloadPath,
+		DownloadPathResolved:        downloadPathResolved,
 		LibraryPath:                 libraryPath,
+		LibraryPathResolved:         libraryPathResolved,
 		TorrentsPath:                torrentsPath,
 		Info:                        info,
 		Platform:                    platform,
 		Language:                    xbmc.GetLanguageISO639_1(),
 		Region:                      xbmc.GetRegion(),
 		TemporaryPath:               info.TempPath,
+		TemporaryPathResolved:       tempPathResolved,
 		ProfilePath:                 info.Profile,
 		HomePath:                    info.Home,
 		XbmcPath:                    info.Xbmc,
@@ -596,12 +673,13 @@ func Reload() *Configuration {
 		OutgoingInterfaces:          settings.ToString("outgoing_interfaces"),
 		TunedStorage:                settings.ToBool("tuned_storage"),
 		DiskCacheSize:               settings.ToInt("disk_cache_size") * 1024 * 1024,
+		HashingThreads:              settings.ToInt("hashing_threads"),
 		UseLibtorrentConfig:         settings.ToBool("use_libtorrent_config"),
 		UseLibtorrentLogging:        settings.ToBool("use_libtorrent_logging"),
 		UseLibtorrentDeadlines:      settings.ToBool("use_libtorrent_deadline"),
 		UseLibtorrentPauseResume:    settings.ToBool("use_libtorrent_pauseresume"),
 		LibtorrentProfile:           settings.ToInt("libtorrent_profile"),
-		MagnetResolveTimeout:        settings.ToInt("magnet_resolve_timeout"),
+		MetadataFetchTimeout:        settings.ToInt("metadata_fetch_timeout"),
 		AddExtraTrackers:            settings.ToInt("add_extra_trackers"),
 		RemoveOriginalTrackers:      settings.ToBool("remove_original_trackers"),
 		ModifyTrackersStrategy:      settings.ToInt("modify_trackers_strategy"),
@@ -626,6 +704,7 @@ func Reload() *Configuration {
 		TraktSyncEnabled:               settings.ToBool("trakt_sync_enabled"),
 		TraktSyncPlaybackEnabled:       settings.ToBool("trakt_sync_playback_enabled"),
 		TraktSyncFrequencyMin:          settings.ToInt("trakt_sync_frequency_min"),
+		TraktSyncOfflineRetry:          settings.ToInt("trakt_sync_offline_retry"),
 		TraktSyncCollections:           settings.ToBool("trakt_sync_collections"),
 		TraktSyncWatchlist:             settings.ToBool("trakt_sync_watchlist"),
 		TraktSyncUserlists:             settings.ToBool("trakt_sync_userlists"),
@@ -686,8 +765,12 @@ func Reload() *Configuration {
 		CustomProviderTimeoutEnabled: settings.ToBool("custom_provider_timeout_enabled"),
 		CustomProviderTimeout:        settings.ToInt("custom_provider_timeout"),
 
-		InternalDNSEnabled:  settings.ToBool("internal_dns_enabled"),
-		InternalDNSSkipIPv6: settings.ToBool("internal_dns_skip_ipv6"),
+		MetadataPrefetchConcurrency: settings.ToInt("metadata_prefetch_concurrency"),
+
+		InternalDNSEnabled:   settings.ToBool("internal_dns_enabled"),
+		InternalDNSSkipIPv6:  settings.ToBool("internal_dns_skip_ipv6"),
+		DNSBenchmark:         settings.ToBool("dns_benchmark"),
+		DNSBenchmarkInterval: settings.ToInt("dns_benchmark_interval"),
 
 		InternalProxyEnabled:     settings.ToBool("internal_proxy_enabled"),
 		InternalProxyLogging:     settings.ToBool("internal_proxy_logging"),
@@ -704,6 +787,7 @@ func Reload() *Configuration {
 		ProxyUseDownload: settings.ToBool("use_proxy_download"),
 
 		CompletedMove:       settings.ToBool("completed_move"),
+		CompletedMoveScope:  settings.ToInt("completed_move_scope"),
 		CompletedMoviesPath: settings.ToString("completed_movies_path"),
 		CompletedShowsPath:  settings.ToString("completed_shows_path"),
 
@@ -754,11 +838,51 @@ func Reload() *Configuration {
 	if newConfig.TraktToken != "" && newConfig.TraktSyncFrequencyMin == 0 {
 		newConfig.TraktSyncFrequencyMin = defaultTraktSyncFrequencyMin
 	}
+	if newConfig.TraktSyncOfflineRetry < 0 {
+		result.warn(fmt.Sprintf("Trakt sync offline retries %d cannot be negative, disabling retries", newConfig.TraktSyncOfflineRetry))
+		newConfig.TraktSyncOfflineRetry = 0
+	} else if newConfig.TraktSyncOfflineRetry > maxTraktSyncOfflineRetry {
+		result.warn(fmt.Sprintf("Trakt sync offline retries %d exceed maximum, using %d", newConfig.TraktSyncOfflineRetry, maxTraktSyncOfflineRetry))
+		newConfig.TraktSyncOfflineRetry = maxTraktSyncOfflineRetry
+	}
 
 	// Setup OSDB language
 	if newConfig.OSDBAutoLanguage || newConfig.OSDBLanguage == "" {
 		newConfig.OSDBLanguage = newConfig.Language
 	}
+	newConfig.OSDBLanguageList = []string{newConfig.OSDBLanguage}
+	if newConfig.OSDBAutoLanguage {
+		subtitleLanguage := ""
+		if _, ok := settings["subtitle_language"]; ok {
+			subtitleLanguage = settings.ToString("subtitle_language")
+		} else {
+			subtitleLanguage = xbmc.SettingsGetSettingValue("locale.subtitlelanguage")
+		}
+
+		if lang := getSubtitleLanguageISO(subtitleLanguage); lang != "" && lang != newConfig.OSDBLanguage {
+			newConfig.OSDBLanguageList = append(newConfig.OSDBLanguageList, lang)
+		}
+	}
+
+	// Providers check is enabled by default, if setting is missing
+	newConfig.CheckProvidersOnReload = true
+	if _, ok := settings["check_providers_on_reload"]; ok {
+		newConfig.CheckProvidersOnReload = settings.ToBool("check_providers_on_reload")
+	}
+
+	// Collect providers allowlist
+	for _, id := range strings.Split(settings.ToString("provider_allowlist"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			newConfig.ProviderAllowlist = append(newConfig.ProviderAllowlist, id)
+		}
+	}
+
+	// Normalize listen interfaces for further host:port usage
+	var listenWarnings []string
+	newConfig.ListenInterfacesList, listenWarnings = parseListenInterfaces(newConfig.ListenInterfaces)
+	for _, w := range listenWarnings {
+		result.warn(w)
+	}
 
 	// Collect proxy settings
 	if newConfig.ProxyEnabled && newConfig.ProxyHost != "" {
@@ -797,6 +921,58 @@ func Reload() *Configuration {
 		newConfig.SessionSave = 10
 	}
 
+	// Magnet metadata fetch should always be limited.
+	// Setting replaces magnet_resolve_timeout, so old value is used until new one is set.
+	if newConfig.MetadataFetchTimeout <= 0 {
+		if newConfig.MetadataFetchTimeout < 0 {
+			result.warn(fmt.Sprintf("Metadata fetch timeout should be positive, got %d", newConfig.MetadataFetchTimeout))
+		}
+
+		if legacyTimeout, ok := settings["magnet_resolve_timeout"].(int); ok && legacyTimeout > 0 {
+			newConfig.MetadataFetchTimeout = legacyTimeout
+		} else {
+			newConfig.MetadataFetchTimeout = defaultMetadataFetchTimeout
+		}
+	}
+
+	if newConfig.CompletedMoveScope != CompletedMovePlayed && newConfig.CompletedMoveScope != CompletedMoveTorrent {
+		result.warn(fmt.Sprintf("Unknown completed move scope %d, moving only played files", newConfig.CompletedMoveScope))
+		newConfig.CompletedMoveScope = CompletedMovePlayed
+	}
+
+	if newConfig.DNSBenchmarkInterval <= 0 {
+		newConfig.DNSBenchmarkInterval = defaultDNSBenchmarkInterval
+	}
+
+	// Validate metadata prefetch limit, defaulting to lower value on weak devices
+	if newConfig.MetadataPrefetchConcurrency <= 0 {
+		if isLowPowerPlatform(platform) {
+			newConfig.MetadataPrefetchConcurrency = defaultMetadataPrefetchLowPower
+		} else {
+			newConfig.MetadataPrefetchConcurrency = defaultMetadataPrefetch
+		}
+	} else if newConfig.MetadataPrefetchConcurrency > maxMetadataPrefetch {
+		result.warn(fmt.Sprintf("Metadata prefetch concurrency %d is too high, using %d", newConfig.MetadataPrefetchConcurrency, maxMetadataPrefetch))
+		newConfig.MetadataPrefetchConcurrency = maxMetadataPrefetch
+	}
+
+	// Validate hashing threads to be within 1..NumCPU, using single thread on weak devices by default
+	if newConfig.HashingThreads <= 0 {
+		if newConfig.HashingThreads < 0 {
+			result.warn(fmt.Sprintf("Hashing threads %d should be positive, using default", newConfig.HashingThreads))
+		}
+
+		if isLowPowerPlatform(platform) {
+			newConfig.HashingThreads = 1
+		} else {
+			newConfig.HashingThreads = runtime.NumCPU()
+		}
+		log.Infof("Using default number of hashing threads: %d", newConfig.HashingThreads)
+	} else if newConfig.HashingThreads > runtime.NumCPU() {
+		result.warn(fmt.Sprintf("Hashing threads %d exceed number of CPUs, using %d", newConfig.HashingThreads, runtime.NumCPU()))
+		newConfig.HashingThreads = runtime.NumCPU()
+	}
+
 	if newConfig.DiskCacheSize == 0 {
 		newConfig.DiskCacheSize = defaultDiskCacheSize
 	}
@@ -810,7 +986,10 @@ func Reload() *Configuration {
 	lock.Lock()
 	config = &newConfig
 	lock.Unlock()
-	go CheckBurst()
+
+	if isBurstCheckNeeded(previous, &newConfig) {
+		go CheckBurst()
+	}
 
 	// Replacing passwords with asterisks
 	configOutput := litter.Sdump(config)
@@ -821,6 +1000,24 @@ func Reload() *Configuration {
 	return config
 }
 
+// OSDBLanguages returns list of languages, in ISO 639-1 format,
+// that should be used for subtitles search.
+func OSDBLanguages() []string {
+	return append([]string{}, Get().OSDBLanguageList...)
+}
+
+// BackgroundProgressMode returns background progress mode,
+// derived from DisableBgProgress and DisableBgProgressPlayback settings.
+func (c *Configuration) BackgroundProgressMode() BgMode {
+	if c.DisableBgProgress {
+		return BgModeOff
+	} else if c.DisableBgProgressPlayback {
+		return BgModeHiddenDuringPlayback
+	}
+
+	return BgModeAlways
+}
+
 // AddonIcon ...
 func AddonIcon() string {
 	return filepath.Join(Get().Info.Path, "icon.png")
@@ -869,6 +1066,110 @@ func PathExists(path string) bool {
 	return true
 }
 
+// IsManagedPath checks whether path, with symlinks resolved, is located
+// inside one of the folders managed by Elementum, so it is safe to modify it.
+func IsManagedPath(path string) bool {
+	c := Get()
+	resolved := resolvePath(path)
+	for _, root := range []string{c.DownloadPathResolved, c.LibraryPathResolved, c.TemporaryPathResolved, resolvePath(c.TorrentsPath)} {
+		if root == "" || root == "." {
+			continue
+		}
+		if IsSubPath(root, resolved) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// prepareTemporaryPath cleans temporary folder and returns the path to use.
+// If folder cannot be cleaned on startup, a unique fallback folder is used instead,
+// and it is kept for the whole session, so that paths of added torrents stay valid.
+func prepareTemporaryPath(result *ReloadResult, path string, previous *Configuration) string {
+	firstRun := previous == nil || previous.Info == nil
+	if firstRun {
+		removeStaleTemporaryPaths(path)
+	} else if strings.HasPrefix(previous.TemporaryPath, path+"-") {
+		path = previous.TemporaryPath
+	}
+
+	// Do not wipe temporary folder if it is a symlink pointing outside of Kodi's temp folder
+	tempPathResolved := resolvePath(path)
+	if tempRoot := resolvePath(filepath.Dir(path)); !IsSubPath(tempRoot, tempPathResolved) {
+		result.warn(fmt.Sprintf("Temporary path %s points to %s, outside of %s, skipping cleanup", path, tempPathResolved, tempRoot))
+	} else if err := removeAll(path); err != nil && !firstRun {
+		result.warn(fmt.Sprintf("Temporary directory %s cannot be cleaned, keeping it: %s", path, err))
+	} else if err != nil {
+		// Stale data can be locked (e.g. on Windows), so we use separate folder for this run
+		fallbackPath := fmt.Sprintf("%s-%d", path, time.Now().Unix())
+		result.warn(fmt.Sprintf("Temporary directory %s cannot be cleaned, using %s instead: %s", path, fallbackPath, err))
+		path = fallbackPath
+	}
+
+	return path
+}
+
+// removeStaleTemporaryPaths removes fallback temporary folders, left from previous runs.
+func removeStaleTemporaryPaths(path string) {
+	files, err := ioutil.ReadDir(filepath.Dir(path))
+	if err != nil {
+		return
+	}
+
+	prefix := filepath.Base(path) + "-"
+	for _, f := range files {
+		if !strings.HasPrefix(f.Name(), prefix) {
+			continue
+		} else if _, err := strconv.ParseInt(strings.TrimPrefix(f.Name(), prefix), 10, 64); err != nil {
+			continue
+		}
+
+		stalePath := filepath.Join(filepath.Dir(path), f.Name())
+		if err := removeAll(stalePath); err != nil {
+			log.Warningf("Could not remove stale temporary directory %s: %s", stalePath, err)
+		} else {
+			log.Infof("Removed stale temporary directory %s", stalePath)
+		}
+	}
+}
+
+// resolvePath returns path with evaluated symlinks.
+// If path does not exist yet - its closest existing parent is resolved.
+func resolvePath(path string) string {
+	if path == "" || path == "." {
+		return path
+	}
+
+	resolved := evalSymlinks(filepath.Clean(path))
+	if resolved != filepath.Clean(path) {
+		log.Debugf("Path %s is resolved to %s", path, resolved)
+	}
+	return resolved
+}
+
+func evalSymlinks(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+
+	parent := filepath.Dir(path)
+	if parent == path {
+		return path
+	}
+	return filepath.Join(evalSymlinks(parent), filepath.Base(path))
+}
+
+// IsSubPath returns whether path is the same as root or is located inside of it.
+func IsSubPath(root string, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
 // IsWritablePath ...
 func IsWritablePath(path string) error {
 	if path == "." {
@@ -908,14 +1209,58 @@ func waitForSettingsClosed() {
 	}
 }
 
+// GetProviderAddons returns all installed providers, both enabled and disabled
+func GetProviderAddons() []Addon {
+	list := make([]Addon, 0)
+	for _, addon := range xbmc.GetAddons("xbmc.python.script", "executable", "all", []string{"name", "version", "enabled"}).Addons {
+		if strings.HasPrefix(addon.ID, "script.elementum.") {
+			list = append(list, Addon{
+				ID:      addon.ID,
+				Name:    addon.Name,
+				Version: addon.Version,
+				Enabled: addon.Enabled,
+			})
+		}
+	}
+	return list
+}
+
+// IsProviderAllowed checks whether provider is in the allowlist,
+// empty allowlist allows all providers.
+func IsProviderAllowed(id string) bool {
+	allowlist := Get().ProviderAllowlist
+	if len(allowlist) == 0 {
+		return true
+	}
+
+	for _, allowed := range allowlist {
+		if allowed == id {
+			return true
+		}
+	}
+	return false
+}
+
+// isBurstCheckNeeded decides whether providers should be checked after reload,
+// which is done on first reload or when providers related settings have changed.
+func isBurstCheckNeeded(previous, current *Configuration) bool {
+	if !current.CheckProvidersOnReload {
+		return false
+	} else if previous == nil || previous.Info == nil {
+		return true
+	}
+
+	return previous.SkipBurstSearch != current.SkipBurstSearch ||
+		previous.CheckProvidersOnReload != current.CheckProvidersOnReload ||
+		strings.Join(previous.ProviderAllowlist, ",") != strings.Join(current.ProviderAllowlist, ",")
+}
+
 // CheckBurst ...
 func CheckBurst() {
 	// Check for enabled providers and Elementum Burst
-	for _, addon := range xbmc.GetAddons("xbmc.python.script", "executable", "all", []string{"name", "version", "enabled"}).Addons {
-		if strings.HasPrefix(addon.ID, "script.elementum.") {
-			if addon.Enabled {
-				return
-			}
+	for _, addon := range GetProviderAddons() {
+		if addon.Enabled {
+			return
 		}
 	}
 
@@ -953,6 +1298,47 @@ func CheckBurst() {
 	}
 }
 
+// isLowPowerPlatform checks whether we are running on a device
+// that should not be overloaded with parallel work, like ARM boxes.
+func isLowPowerPlatform(platform *xbmc.Platform) bool {
+	if platform == nil {
+		return false
+	}
+
+	return strings.HasPrefix(strings.ToLower(platform.Arch), "arm") || strings.ToLower(platform.OS) == "android"
+}
+
+// getSubtitleLanguageISO converts Kodi's subtitle language setting to ISO 639-1,
+// ignoring special values that do not point to exact language.
+func getSubtitleLanguageISO(language string) string {
+	switch strings.ToLower(strings.TrimSpace(language)) {
+	case "", "none", "original", "default", "forced_only":
+		return ""
+	}
+
+	return xbmc.ConvertLanguage(language, xbmc.Iso639_1)
+}
+
+// fetchXbmcSettings gets all addon settings from Kodi,
+// retrying with increasing delay if returned list looks incomplete.
+func fetchXbmcSettings() (ret []*xbmc.Setting) {
+	delay := xbmcSettingsFetchDelay
+	for try := 1; try <= xbmcSettingsFetchTries; try++ {
+		ret = getAllSettings()
+		if len(ret) >= minXbmcSettingsCount {
+			return
+		}
+
+		if try < xbmcSettingsFetchTries {
+			log.Warningf("Got only %d settings from Kodi, retrying in %s (%d out of %d)", len(ret), delay, try, xbmcSettingsFetchTries)
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+
+	return
+}
+
 func findExistingPath(paths []string, addon string) string {
 	// We add plugin folder to avoid getting dummy path, we should take care only for real folder
 	for _, v := range paths {
@@ -967,6 +1353,56 @@ func findExistingPath(paths []string, addon string) string {
 	return ""
 }
 
+// parseListenInterfaces splits comma-separated list of interfaces,
+// wraps IPv6 addresses into brackets and drops entries that cannot be used.
+// Link-local IPv6 addresses can have a zone, like fe80::1%eth0, which should be an existing interface.
+func parseListenInterfaces(value string) (ret []string, warnings []string) {
+	ret = []string{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		host := entry
+		bracketed := strings.HasPrefix(entry, "[") && strings.HasSuffix(entry, "]")
+		if bracketed {
+			host = entry[1 : len(entry)-1]
+		}
+
+		addr, zone := host, ""
+		if i := strings.LastIndex(host, "%"); i >= 0 {
+			addr, zone = host[:i], host[i+1:]
+		}
+
+		ip := net.ParseIP(addr)
+		switch {
+		case ip != nil && ip.To4() == nil:
+			if zone == "" {
+				ret = append(ret, "["+ip.String()+"]")
+			} else if _, err := interfaceByName(zone); err != nil {
+				warnings = append(warnings, fmt.Sprintf("Skipping malformed listen interface '%s': unknown zone: %s", entry, err))
+			} else {
+				ret = append(ret, "["+ip.String()+"%"+zone+"]")
+			}
+		case bracketed:
+			warnings = append(warnings, fmt.Sprintf("Skipping malformed listen interface '%s': not a valid IPv6 address", entry))
+		case zone != "":
+			warnings = append(warnings, fmt.Sprintf("Skipping malformed listen interface '%s': zone is allowed only for IPv6 addresses", entry))
+		case ip != nil:
+			ret = append(ret, ip.To4().String())
+		default:
+			if _, err := interfaceByName(entry); err != nil {
+				warnings = append(warnings, fmt.Sprintf("Skipping malformed listen interface '%s': %s", entry, err))
+				continue
+			}
+			ret = append(ret, entry)
+		}
+	}
+
+	return
+}
+
 func getKodiBufferSize() int {
 	xmlFile, err := os.Open(filepath.Join(xbmc.TranslatePath("special://userdata"), "advancedsettings.xml"))
 	if err != nil {
@@ -0,0 +1,274 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elgatito/elementum/xbmc"
+)
+
+func TestParseListenInterfaces(t *testing.T) {
+	defer func(f func(string) (*net.Interface, error)) { interfaceByName = f }(interfaceByName)
+	interfaceByName = func(name string) (*net.Interface, error) {
+		if name == "eth0" {
+			return &net.Interface{Name: name}, nil
+		}
+		return nil, errors.New("no such network interface")
+	}
+
+	tests := []struct {
+		name     string
+		value    string
+		expected []string
+		warnings int
+	}{
+		{"empty", "", []string{}, 0},
+		{"ipv4", "192.168.1.10", []string{"192.168.1.10"}, 0},
+		{"ipv4 any", "0.0.0.0", []string{"0.0.0.0"}, 0},
+		{"ipv4 bracketed", "[192.168.1.10]", []string{}, 1},
+		{"ipv4 with zone", "192.168.1.10%eth0", []string{}, 1},
+		{"ipv6", "2001:db8::1", []string{"[2001:db8::1]"}, 0},
+		{"ipv6 bracketed", "[2001:db8::1]", []string{"[2001:db8::1]"}, 0},
+		{"ipv6 zoned", "fe80::1%eth0", []string{"[fe80::1%eth0]"}, 0},
+		{"ipv6 zoned bracketed", "[fe80::1%eth0]", []string{"[fe80::1%eth0]"}, 0},
+		{"ipv6 unknown zone", "fe80::1%wlan9", []string{}, 1},
+		{"ipv6 malformed", "[2001:db8::zz]", []string{}, 1},
+		{"interface", "eth0", []string{"eth0"}, 0},
+		{"unknown interface", "wlan9", []string{}, 1},
+		{"list", " 10.0.0.1 , eth0,, ::1 , wlan9", []string{"10.0.0.1", "eth0", "[::1]"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ret, warnings := parseListenInterfaces(tt.value)
+			if !reflect.DeepEqual(ret, tt.expected) {
+				t.Errorf("parseListenInterfaces(%q) = %v, expected %v", tt.value, ret, tt.expected)
+			}
+			if len(warnings) != tt.warnings {
+				t.Errorf("parseListenInterfaces(%q) warnings = %v, expected %d", tt.value, warnings, tt.warnings)
+			}
+		})
+	}
+}
+
+func TestIsManagedPathWithSymlinks(t *testing.T) {
+	root := t.TempDir()
+	downloads := filepath.Join(root, "downloads")
+	outside := filepath.Join(root, "outside")
+	for _, dir := range []string{downloads, outside} {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Download path, as set in settings, is a symlink to the real folder
+	link := filepath.Join(root, "link")
+	if err := os.Symlink(downloads, link); err != nil {
+		t.Skipf("Symlinks are not supported: %s", err)
+	}
+	// Symlink inside of download folder, that points outside of it
+	escape := filepath.Join(downloads, "escape")
+	if err := os.Symlink(outside, escape); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(c *Configuration) { config = c }(config)
+	config = &Configuration{
+		DownloadPath:         link,
+		DownloadPathResolved: resolvePath(link),
+	}
+
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{link, true},
+		{filepath.Join(link, "Movie"), true},
+		{downloads, true},
+		{filepath.Join(downloads, "Movie"), true},
+		{escape, false},
+		{filepath.Join(link, "escape"), false},
+		{outside, false},
+		{root, false},
+		{downloads + "..foo", false},
+	}
+
+	for _, tt := range tests {
+		if managed := IsManagedPath(tt.path); managed != tt.expected {
+			t.Errorf("IsManagedPath(%q) = %v, expected %v", tt.path, managed, tt.expected)
+		}
+	}
+}
+
+func fakeSettings(count int) []*xbmc.Setting {
+	ret := make([]*xbmc.Setting, 0, count)
+	for i := 0; i < count; i++ {
+		ret = append(ret, &xbmc.Setting{Key: fmt.Sprintf("setting_%d", i), Type: "text"})
+	}
+	return ret
+}
+
+func TestReloadKeepsPreviousOnPartialSettings(t *testing.T) {
+	defer func(f func() []*xbmc.Setting, d time.Duration) {
+		getAllSettings = f
+		xbmcSettingsFetchDelay = d
+	}(getAllSettings, xbmcSettingsFetchDelay)
+	defer func(c *Configuration) { config = c }(config)
+
+	calls := 0
+	getAllSettings = func() []*xbmc.Setting {
+		calls++
+		return fakeSettings(10)
+	}
+	xbmcSettingsFetchDelay = time.Millisecond
+
+	previous := &Configuration{
+		Info:         &xbmc.AddonInfo{ID: "plugin.video.elementum"},
+		DownloadPath: "/downloads",
+	}
+	config = previous
+
+	result, err := ReloadWithResult()
+	if err != nil {
+		t.Fatalf("ReloadWithResult() returned error: %s", err)
+	}
+	if calls != xbmcSettingsFetchTries {
+		t.Errorf("Settings fetched %d times, expected %d", calls, xbmcSettingsFetchTries)
+	}
+	if result.Config != previous || Get() != previous {
+		t.Errorf("Previous configuration was not kept")
+	}
+	if !result.Changed.IsEmpty() {
+		t.Errorf("Configuration changed: %v", result.Changed.Fields)
+	}
+	if len(result.Warnings) != 1 {
+		t.Errorf("Warnings = %v, expected 1 warning", result.Warnings)
+	}
+}
+
+func TestFetchXbmcSettingsRetries(t *testing.T) {
+	defer func(f func() []*xbmc.Setting, d time.Duration) {
+		getAllSettings = f
+		xbmcSettingsFetchDelay = d
+	}(getAllSettings, xbmcSettingsFetchDelay)
+
+	calls := 0
+	getAllSettings = func() []*xbmc.Setting {
+		calls++
+		if calls < 3 {
+			return fakeSettings(10)
+		}
+		return fakeSettings(minXbmcSettingsCount)
+	}
+	xbmcSettingsFetchDelay = time.Millisecond
+
+	if settings := fetchXbmcSettings(); len(settings) != minXbmcSettingsCount {
+		t.Errorf("fetchXbmcSettings() returned %d settings, expected %d", len(settings), minXbmcSettingsCount)
+	}
+	if calls != 3 {
+		t.Errorf("Settings fetched %d times, expected 3", calls)
+	}
+}
+
+func TestPrepareTemporaryPathWithLockedFiles(t *testing.T) {
+	defer func(f func(string) error) { removeAll = f }(removeAll)
+
+	root := t.TempDir()
+	path := filepath.Join(root, "elementum")
+	stale := filepath.Join(root, "elementum-1600000000")
+	other := filepath.Join(root, "elementum-other")
+	for _, dir := range []string{path, stale, other} {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Emulates files, locked by another process
+	removeAll = func(p string) error {
+		if p == path {
+			return errors.New("The process cannot access the file because it is being used by another process")
+		}
+		return os.RemoveAll(p)
+	}
+
+	result := &ReloadResult{}
+	fallback := prepareTemporaryPath(result, path, &Configuration{})
+	if fallback == path || !strings.HasPrefix(fallback, path+"-") {
+		t.Errorf("prepareTemporaryPath() = %s, expected fallback path", fallback)
+	}
+	if len(result.Warnings) != 1 {
+		t.Errorf("Warnings = %v, expected 1 warning", result.Warnings)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("Stale fallback directory %s was not removed", stale)
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("Unrelated directory %s was removed", other)
+	}
+
+	// Fallback path is kept on next reloads, even if it cannot be cleaned
+	previous := &Configuration{Info: &xbmc.AddonInfo{}, TemporaryPath: fallback}
+	if next := prepareTemporaryPath(&ReloadResult{}, path, previous); next != fallback {
+		t.Errorf("prepareTemporaryPath() = %s on reload, expected %s", next, fallback)
+	}
+
+	removeAll = func(string) error { return errors.New("locked") }
+	result = &ReloadResult{}
+	if next := prepareTemporaryPath(result, path, previous); next != fallback {
+		t.Errorf("prepareTemporaryPath() = %s on reload with locked files, expected %s", next, fallback)
+	}
+	if len(result.Warnings) != 1 {
+		t.Errorf("Warnings = %v, expected 1 warning", result.Warnings)
+	}
+}
+
+func TestBackgroundProgressMode(t *testing.T) {
+	tests := []struct {
+		disable         bool
+		disablePlayback bool
+		expected        BgMode
+	}{
+		{false, false, BgModeAlways},
+		{false, true, BgModeHiddenDuringPlayback},
+		{true, false, BgModeOff},
+		{true, true, BgModeOff},
+	}
+
+	for _, tt := range tests {
+		c := &Configuration{DisableBgProgress: tt.disable, DisableBgProgressPlayback: tt.disablePlayback}
+		if mode := c.BackgroundProgressMode(); mode != tt.expected {
+			t.Errorf("BackgroundProgressMode() with DisableBgProgress=%v, DisableBgProgressPlayback=%v = %d, expected %d", tt.disable, tt.disablePlayback, mode, tt.expected)
+		}
+	}
+}
+
+func TestIsSubPath(t *testing.T) {
+	root := filepath.FromSlash("/downloads/Movie")
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{"/downloads/Movie", true},
+		{"/downloads/Movie/", true},
+		{"/downloads/Movie/Extras", true},
+		{"/downloads/Movie/..foo", true},
+		{"/downloads/Movie..foo", false},
+		{"/downloads/..foo", false},
+		{"/downloads", false},
+		{"/downloads/Movie/../Other", false},
+		{"/other", false},
+	}
+
+	for _, tt := range tests {
+		if sub := IsSubPath(root, filepath.FromSlash(tt.path)); sub != tt.expected {
+			t.Errorf("IsSubPath(%q, %q) = %v, expected %v", root, tt.path, sub, tt.expected)
+		}
+	}
+}
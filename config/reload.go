@@ -0,0 +1,149 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ReloadResult describes the outcome of configuration reload.
+// It is returned by ReloadWithResult and passed to reload subscribers.
+type ReloadResult struct {
+	// Config is the configuration in use after reload.
+	// If reload has failed - it is the previous configuration.
+	Config *Configuration
+	// Changed lists configuration fields that differ from previous configuration.
+	Changed *ConfigDiff
+	// Warnings are non-fatal problems, found in settings, that were fixed or skipped.
+	Warnings []string
+	// Errors are fatal problems, that prevented configuration from being applied.
+	Errors   []error
+	Duration time.Duration
+}
+
+// ConfigDiff contains names of Configuration fields changed during reload.
+type ConfigDiff struct {
+	Fields []string
+}
+
+// ReloadSubscriber is called after each successful configuration reload.
+type ReloadSubscriber func(result *ReloadResult)
+
+var (
+	reloadMu          sync.Mutex
+	reloadSubscribers []ReloadSubscriber
+	lastReloadResult  *ReloadResult
+)
+
+// ReloadWithResult reloads configuration from addon settings.
+// Unlike Reload, it never exits the process: failures are reported in returned error
+// and in ReloadResult.Errors, while previous configuration is kept in use.
+func ReloadWithResult() (result *ReloadResult, err error) {
+	result, err = runReload()
+	if err != nil {
+		return
+	}
+
+	reloadMu.Lock()
+	subscribers := append([]ReloadSubscriber{}, reloadSubscribers...)
+	reloadMu.Unlock()
+
+	for _, s := range subscribers {
+		s(result)
+	}
+	return
+}
+
+func runReload() (result *ReloadResult, err error) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	started := time.Now()
+	previous := Get()
+	result = &ReloadResult{
+		Config:  previous,
+		Changed: &ConfigDiff{},
+	}
+
+	defer func() {
+		result.Duration = time.Since(started)
+		lastReloadResult = result
+
+		if r := recover(); r != nil {
+			if e, ok := r.(error); ok {
+				err = e
+			} else {
+				err = fmt.Errorf("%v", r)
+			}
+			result.Errors = append(result.Errors, err)
+			log.Errorf("Configuration reload failed in %s: %s", result.Duration, err)
+			return
+		}
+
+		log.Infof("Configuration reloaded in %s, changed fields: %v", result.Duration, result.Changed.Fields)
+	}()
+
+	result.Config = reload(result)
+	result.Changed = diffConfigurations(previous, result.Config)
+
+	return
+}
+
+// LastReloadResult returns result of the latest configuration reload, or nil if there was none.
+// It is meant for diagnostics, callers of ReloadWithResult should use returned result.
+func LastReloadResult() *ReloadResult {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	return lastReloadResult
+}
+
+// OnReload registers a subscriber, that is called after each successful reload.
+func OnReload(s ReloadSubscriber) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	reloadSubscribers = append(reloadSubscribers, s)
+}
+
+// IsEmpty returns whether nothing has changed.
+func (d *ConfigDiff) IsEmpty() bool {
+	return d == nil || len(d.Fields) == 0
+}
+
+// Has returns whether field with given name has changed.
+func (d *ConfigDiff) Has(field string) bool {
+	if d == nil {
+		return false
+	}
+
+	for _, f := range d.Fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *ReloadResult) warn(message string) {
+	log.Warning(message)
+	r.Warnings = append(r.Warnings, message)
+}
+
+func diffConfigurations(previous, current *Configuration) *ConfigDiff {
+	diff := &ConfigDiff{Fields: []string{}}
+	if previous == nil || current == nil {
+		return diff
+	}
+
+	prev := reflect.ValueOf(previous).Elem()
+	cur := reflect.ValueOf(current).Elem()
+	for i := 0; i < cur.NumField(); i++ {
+		if !reflect.DeepEqual(prev.Field(i).Interface(), cur.Field(i).Interface()) {
+			diff.Fields = append(diff.Fields, cur.Type().Field(i).Name)
+		}
+	}
+
+	return diff
+}
@@ -251,6 +251,11 @@ func Init() {
 		}()
 	}
 
+	// Catch up with Trakt sync, that was deferred before restart
+	if isTraktSyncOverdue() {
+		PlanTraktUpdate()
+	}
+
 	log.Notice("Warming up caches...")
 	go func() {
 		time.Sleep(30 * time.Second)
@@ -691,15 +696,9 @@ func RemoveMovie(tmdbID int) (*tmdb.Movie, []string, error) {
 		log.Warningf("Cannot find directories with strm files")
 		return movie, nil, errors.New("LOCALIZE[30282]")
 	}
-	ret := []string{}
-	for path := range paths {
-		if err := os.RemoveAll(path); err != nil {
-			log.Error(err)
-			return movie, nil, err
-		}
-
-		ret = append(ret, path)
-		log.Warningf("Directory %s removed from disk", path)
+	ret, err := removeLibraryPaths(paths)
+	if err != nil {
+		return movie, ret, err
 	}
 
 	log.Warningf("%s removed from library", movie.Title)
@@ -728,15 +727,9 @@ func RemoveShow(tmdbID string) (*tmdb.Show, []string, error) {
 		log.Warningf("Cannot find directories with strm files")
 		return show, nil, errors.New("LOCALIZE[30282]")
 	}
-	ret := []string{}
-	for path := range paths {
-		if err := os.RemoveAll(path); err != nil {
-			log.Error(err)
-			return show, nil, err
-		}
-
-		ret = append(ret, path)
-		log.Warningf("Directory %s removed from disk", path)
+	ret, err := removeLibraryPaths(paths)
+	if err != nil {
+		return show, ret, err
 	}
 
 	log.Warningf("%s removed from library", show.Name)
@@ -1316,6 +1309,32 @@ func getShowPathsByTMDB(id int) (ret map[string]bool) {
 	return
 }
 
+// removeLibraryPaths removes directories from disk, keeping the ones outside of Elementum folders.
+// Returns removed directories, and an error if any directory was kept.
+func removeLibraryPaths(paths map[string]bool) ([]string, error) {
+	ret := []string{}
+	kept := []string{}
+	for path := range paths {
+		if !config.IsManagedPath(path) {
+			log.Warningf("Directory %s is outside of Elementum folders, not removing it", path)
+			kept = append(kept, path)
+			continue
+		}
+		if err := os.RemoveAll(path); err != nil {
+			log.Error(err)
+			return ret, err
+		}
+
+		ret = append(ret, path)
+		log.Warningf("Directory %s removed from disk", path)
+	}
+
+	if len(kept) > 0 {
+		return ret, fmt.Errorf("Directories outside of Elementum folders were not removed: %s", strings.Join(kept, ", "))
+	}
+	return ret, nil
+}
+
 func getMoviePaths(movie *tmdb.Movie) map[string]bool {
 	paths := getMoviePathsByTMDB(movie.ID)
 	if len(paths) != 0 {
@@ -11,6 +11,7 @@ import (
 	"github.com/elgatito/elementum/library/uid"
 	"github.com/elgatito/elementum/tmdb"
 	"github.com/elgatito/elementum/trakt"
+	"github.com/elgatito/elementum/util"
 	"github.com/elgatito/elementum/xbmc"
 )
 
@@ -19,6 +20,13 @@ var (
 	IsTraktInitialized bool
 	isKodiAdded        bool
 	isKodiUpdated      bool
+
+	traktSyncRetries int
+)
+
+const (
+	traktSyncRetryDelay    = 30 * time.Second
+	traktSyncRetryMaxDelay = 30 * time.Minute
 )
 
 // RefreshTrakt gets user activities from Trakt
@@ -57,6 +65,8 @@ func RefreshTrakt() error {
 		log.Warningf("Cannot get activities: %s", err)
 		if err == trakt.ErrLocked {
 			go trakt.NotifyLocked()
+		} else if trakt.ShouldRetryTraktSync(err) {
+			scheduleTraktSyncRetry()
 		}
 
 		return err
@@ -68,13 +78,22 @@ func RefreshTrakt() error {
 	isFirstRun := !IsTraktInitialized || isKodiUpdated
 	if !lastActivities.All.After(previousActivities.All) && !isFirstRun {
 		log.Debugf("Skipping Trakt sync due to stale activities")
+		markTraktSynced()
 		return nil
 	}
 
 	isErrored := false
+	isRetryNeeded := false
+	fail := func(err error) {
+		isErrored = true
+		isRetryNeeded = isRetryNeeded || trakt.ShouldRetryTraktSync(err)
+	}
 	defer func() {
 		if !isErrored {
 			_ = cacheStore.Set(cache.TraktActivitiesKey, lastActivities, cache.TraktActivitiesExpire)
+			markTraktSynced()
+		} else if isRetryNeeded {
+			scheduleTraktSyncRetry()
 		}
 	}()
 
@@ -92,86 +111,125 @@ func RefreshTrakt() error {
 	// Movies
 	if isFirstRun || isKodiAdded || lastActivities.Movies.WatchedAt.After(previousActivities.Movies.WatchedAt) {
 		if err := RefreshTraktWatched(MovieType, lastActivities.Movies.WatchedAt.After(previousActivities.Movies.WatchedAt)); err != nil {
-			isErrored = true
+			fail(err)
 		}
 	}
 	if isFirstRun || lastActivities.Movies.CollectedAt.After(previousActivities.Movies.CollectedAt) {
 		if err := RefreshTraktCollected(MovieType, lastActivities.Movies.CollectedAt.After(previousActivities.Movies.CollectedAt)); err != nil {
-			isErrored = true
+			fail(err)
 		}
 	}
 	if isFirstRun || lastActivities.Movies.WatchlistedAt.After(previousActivities.Movies.WatchlistedAt) {
 		if err := RefreshTraktWatchlisted(MovieType, lastActivities.Movies.WatchlistedAt.After(previousActivities.Movies.WatchlistedAt)); err != nil {
-			isErrored = true
+			fail(err)
 		}
 	}
 	if isFirstRun || isKodiAdded || lastActivities.Movies.PausedAt.After(previousActivities.Movies.PausedAt) {
 		if err := RefreshTraktPaused(MovieType, lastActivities.Movies.PausedAt.After(previousActivities.Movies.PausedAt)); err != nil {
-			isErrored = true
+			fail(err)
 		}
 	}
 	if isFirstRun || lastActivities.Movies.HiddenAt.After(previousActivities.Movies.HiddenAt) {
 		if err := RefreshTraktHidden(MovieType, lastActivities.Movies.HiddenAt.After(previousActivities.Movies.HiddenAt)); err != nil {
-			isErrored = true
+			fail(err)
 		}
 	}
 
 	// Episodes
 	if isFirstRun || isKodiAdded || lastActivities.Episodes.WatchedAt.After(previousActivities.Episodes.WatchedAt) {
 		if err := RefreshTraktWatched(EpisodeType, lastActivities.Episodes.WatchedAt.After(previousActivities.Episodes.WatchedAt)); err != nil {
-			isErrored = true
+			fail(err)
 		}
 	}
 	if isFirstRun || lastActivities.Episodes.CollectedAt.After(previousActivities.Episodes.CollectedAt) {
 		if err := RefreshTraktCollected(EpisodeType, lastActivities.Episodes.CollectedAt.After(previousActivities.Episodes.CollectedAt)); err != nil {
-			isErrored = true
+			fail(err)
 		}
 	}
 	if isFirstRun || lastActivities.Episodes.WatchlistedAt.After(previousActivities.Episodes.WatchlistedAt) {
 		if err := RefreshTraktWatchlisted(EpisodeType, lastActivities.Episodes.WatchlistedAt.After(previousActivities.Episodes.WatchlistedAt)); err != nil {
-			isErrored = true
+			fail(err)
 		}
 	}
 	if isFirstRun || isKodiAdded || lastActivities.Episodes.PausedAt.After(previousActivities.Episodes.PausedAt) {
 		if err := RefreshTraktPaused(EpisodeType, lastActivities.Episodes.PausedAt.After(previousActivities.Episodes.PausedAt)); err != nil {
-			isErrored = true
+			fail(err)
 		}
 	}
 
 	// Shows
 	if isFirstRun || lastActivities.Shows.WatchlistedAt.After(previousActivities.Shows.WatchlistedAt) {
 		if err := RefreshTraktWatchlisted(ShowType, lastActivities.Shows.WatchlistedAt.After(previousActivities.Shows.WatchlistedAt)); err != nil {
-			isErrored = true
+			fail(err)
 		}
 	}
 	if isFirstRun || lastActivities.Shows.HiddenAt.After(previousActivities.Shows.HiddenAt) {
 		if err := RefreshTraktHidden(ShowType, lastActivities.Shows.HiddenAt.After(previousActivities.Shows.HiddenAt)); err != nil {
-			isErrored = true
+			fail(err)
 		}
 	}
 
 	// Seasons
 	if isFirstRun || lastActivities.Seasons.WatchlistedAt.After(previousActivities.Seasons.WatchlistedAt) {
 		if err := RefreshTraktWatchlisted(SeasonType, lastActivities.Seasons.WatchlistedAt.After(previousActivities.Seasons.WatchlistedAt)); err != nil {
-			isErrored = true
+			fail(err)
 		}
 	}
 	if isFirstRun || lastActivities.Seasons.HiddenAt.After(previousActivities.Seasons.HiddenAt) {
 		if err := RefreshTraktHidden(SeasonType, lastActivities.Seasons.HiddenAt.After(previousActivities.Seasons.HiddenAt)); err != nil {
-			isErrored = true
+			fail(err)
 		}
 	}
 
 	// Lists
 	if isFirstRun || lastActivities.Lists.UpdatedAt.After(previousActivities.Lists.UpdatedAt) {
 		if err := RefreshTraktLists(lastActivities.Lists.UpdatedAt.After(previousActivities.Lists.UpdatedAt)); err != nil {
-			isErrored = true
+			fail(err)
 		}
 	}
 
 	return nil
 }
 
+// scheduleTraktSyncRetry plans Trakt sync after a network failure,
+// with increasing delay, until configured number of retries is reached.
+func scheduleTraktSyncRetry() {
+	if traktSyncRetries >= config.Get().TraktSyncOfflineRetry {
+		if traktSyncRetries > 0 {
+			log.Warningf("TraktSync: giving up after %d retries, waiting for next scheduled sync", traktSyncRetries)
+		}
+		traktSyncRetries = 0
+		return
+	}
+
+	delay := traktSyncRetryDelay * time.Duration(1<<uint(traktSyncRetries))
+	if delay > traktSyncRetryMaxDelay {
+		delay = traktSyncRetryMaxDelay
+	}
+	traktSyncRetries++
+	log.Infof("TraktSync: network is not available, retrying in %s (%d out of %d)", delay, traktSyncRetries, config.Get().TraktSyncOfflineRetry)
+
+	time.AfterFunc(delay, PlanTraktUpdate)
+}
+
+// markTraktSynced saves time of last successful Trakt sync
+func markTraktSynced() {
+	traktSyncRetries = 0
+	_ = cacheStore.Set(cache.TraktLastSyncKey, time.Now(), cache.TraktLastSyncExpire)
+}
+
+// isTraktSyncOverdue checks whether last successful Trakt sync
+// was earlier than sync frequency, e.g. it was deferred due to network failures.
+// Returns false if there was no sync yet.
+func isTraktSyncOverdue() bool {
+	var lastSync time.Time
+	if err := cacheStore.Get(cache.TraktLastSyncKey, &lastSync); err != nil {
+		return false
+	}
+
+	return time.Since(lastSync) > time.Duration(util.Max(1, config.Get().TraktSyncFrequencyMin))*time.Minute
+}
+
 // RefreshTraktWatched ...
 func RefreshTraktWatched(itemType int, isRefreshNeeded bool) error {
 	if config.Get().TraktToken == "" || !config.Get().TraktSyncWatched {
@@ -172,8 +172,14 @@ func main() {
 		handler.ServeHTTP(w, r)
 	}))
 	http.Handle("/reload", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		s.Reconfigure()
-		w.Write([]byte("true"))
+		result, err := s.Reconfigure()
+		log.Infof("Reload finished in %s with %d warning(s), changed: %v", result.Duration, len(result.Warnings), result.Changed.Fields)
+		// Warnings and errors are already logged, and can be found in /debug/all
+		if err != nil {
+			w.Write([]byte("false"))
+		} else {
+			w.Write([]byte("true"))
+		}
 	}))
 	http.Handle("/notification", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		Notification(r, s)
@@ -87,6 +87,12 @@ func GetPayloads(searchString string, languages []string, preferredLanguage stri
 	// (there is a separate setting for that) in Player settings.
 	if !config.Get().OSDBAutoLanguage && config.Get().OSDBLanguage != "" {
 		languages = []string{config.Get().OSDBLanguage}
+	} else if config.Get().OSDBAutoLanguage {
+		for _, lang := range config.OSDBLanguages() {
+			if !contains(languages, lang) {
+				languages = append(languages, lang)
+			}
+		}
 	}
 
 	// If there is preferred language - we should use it
@@ -108,17 +114,22 @@ func GetPayloads(searchString string, languages []string, preferredLanguage stri
 	)
 	log.Debugf("Fetched VideoPlayer labels: %#v", labels)
 
-	for i, lang := range languages {
-		if lang == "Portuguese (Brazil)" {
-			languages[i] = "pob"
-		} else {
-			isoLang := xbmc.ConvertLanguage(lang, xbmc.Iso639_2)
+	isoLanguages := make([]string, 0, len(languages))
+	for _, lang := range languages {
+		isoLang := "pob"
+		if lang != "Portuguese (Brazil)" {
+			isoLang = xbmc.ConvertLanguage(lang, xbmc.Iso639_2)
 			if isoLang == "gre" {
 				isoLang = "ell"
 			}
-			languages[i] = isoLang
+		}
+
+		// Same language can come in different formats, like name and code
+		if !contains(isoLanguages, isoLang) {
+			isoLanguages = append(isoLanguages, isoLang)
 		}
 	}
+	languages = isoLanguages
 
 	payloads := []SearchPayload{}
 	if searchString != "" {
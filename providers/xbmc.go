@@ -74,10 +74,21 @@ func CallbackHandler(ctx *gin.Context) {
 
 func getSearchers() []interface{} {
 	list := make([]interface{}, 0)
-	for _, addon := range xbmc.GetAddons("xbmc.python.script", "executable", true).Addons {
-		if strings.HasPrefix(addon.ID, "script.elementum.") {
-			list = append(list, NewAddonSearcher(addon.ID))
+	for _, addon := range config.GetProviderAddons() {
+		if !config.IsProviderAllowed(addon.ID) {
+			continue
+		} else if !addon.Enabled {
+			if len(config.Get().ProviderAllowlist) == 0 {
+				continue
+			}
+
+			// Kodi does not execute disabled addons, so allowlisted provider has to be enabled
+			log.Warningf("Provider %s is in the allowlist, but is disabled in Kodi, enabling it", addon.ID)
+			xbmc.SetAddonEnabled(addon.ID, true)
+			xbmc.Notify("Elementum", "LOCALIZE[30671];;"+addon.Name, config.AddonIcon())
 		}
+
+		list = append(list, NewAddonSearcher(addon.ID))
 	}
 	return list
 }
@@ -4,6 +4,9 @@ import (
 	"context"
 	"strings"
 	"sync"
+	"time"
+
+	"github.com/elgatito/elementum/config"
 
 	"github.com/anacrolix/missinggo/perf"
 	"github.com/bogdanovich/dns_resolver"
@@ -43,15 +46,32 @@ var (
 		"uu",
 	}
 
+	opennicServers = []string{"193.183.98.66", "172.104.136.243", "89.18.27.167"}
+
+	// opennicBenchmarkHosts are domains, used to measure servers latency.
+	// First one is served by Opennic, others are common domains,
+	// used if Opennic domain cannot be resolved anymore.
+	opennicBenchmarkHosts = []string{"grep.geek", "example.com"}
+
 	commonResolver  = doh.Use(doh.CloudflareProvider, doh.GoogleProvider)
-	opennicResolver = dns_resolver.New([]string{"193.183.98.66", "172.104.136.243", "89.18.27.167"})
+	opennicResolver = dns_resolver.New(opennicServers)
 
 	dnsCacheResults sync.Map
 	dnsCacheLocks   sync.Map
+
+	dnsBenchmarkMu       sync.RWMutex
+	dnsBenchmarkCloser   chan struct{}
+	dnsBenchmarkInterval time.Duration
+	dnsBestServer        string
+	dnsBestResolver      *dns_resolver.DnsResolver
 )
 
 func init() {
 	commonResolver.EnableCache(true)
+
+	config.OnReload(func(*config.ReloadResult) {
+		reloadDNSBenchmark()
+	})
 }
 
 func resolve(addr string) ([]string, error) {
@@ -141,7 +161,12 @@ func resolveAddr(host string) (ips []string) {
 		return strings.Split(cached.(string), ",")
 	}
 
-	ipsResolved, err := opennicResolver.LookupHost(host)
+	resolver := getOpennicResolver()
+	ipsResolved, err := resolver.LookupHost(host)
+	if (err != nil || len(ipsResolved) == 0) && resolver != opennicResolver {
+		// Fastest server can fail at any time, so we failover to all servers
+		ipsResolved, err = opennicResolver.LookupHost(host)
+	}
 	if err == nil && len(ipsResolved) > 0 {
 		for _, i := range ipsResolved {
 			ips = append(ips, i.String())
@@ -152,3 +177,124 @@ func resolveAddr(host string) (ips []string) {
 
 	return
 }
+
+// BestOpennicServer returns Opennic DNS server, currently selected as the fastest one,
+// or empty string if benchmarking is disabled or not yet finished.
+// Only Opennic zones are resolved with it, other domains are resolved with DoH providers.
+func BestOpennicServer() string {
+	dnsBenchmarkMu.RLock()
+	defer dnsBenchmarkMu.RUnlock()
+
+	return dnsBestServer
+}
+
+// getOpennicResolver returns resolver for the fastest server if DNS benchmark is enabled,
+// otherwise resolver that is using all servers.
+func getOpennicResolver() *dns_resolver.DnsResolver {
+	dnsBenchmarkMu.RLock()
+	defer dnsBenchmarkMu.RUnlock()
+
+	if dnsBestResolver != nil {
+		return dnsBestResolver
+	}
+	return opennicResolver
+}
+
+// reloadDNSBenchmark starts or stops periodic benchmark of Opennic DNS servers,
+// depending on current configuration. Benchmark is useless if internal DNS is not used.
+func reloadDNSBenchmark() {
+	enabled := config.Get().InternalDNSEnabled && config.Get().DNSBenchmark
+	interval := time.Duration(config.Get().DNSBenchmarkInterval) * time.Minute
+
+	dnsBenchmarkMu.Lock()
+	defer dnsBenchmarkMu.Unlock()
+
+	if dnsBenchmarkCloser != nil {
+		if enabled && interval == dnsBenchmarkInterval {
+			return
+		}
+
+		close(dnsBenchmarkCloser)
+		dnsBenchmarkCloser = nil
+	}
+
+	dnsBestServer = ""
+	dnsBestResolver = nil
+	if !enabled {
+		return
+	}
+
+	dnsBenchmarkCloser = make(chan struct{})
+	dnsBenchmarkInterval = interval
+	go runDNSBenchmark(dnsBenchmarkCloser, interval)
+}
+
+func runDNSBenchmark(closer chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	benchmarkDNS(closer)
+	for {
+		select {
+		case <-closer:
+			return
+		case <-ticker.C:
+			benchmarkDNS(closer)
+		}
+	}
+}
+
+// benchmarkDNS queries each Opennic server and selects the one that answered fastest.
+func benchmarkDNS(closer chan struct{}) {
+	best := ""
+	bestDuration := time.Duration(0)
+
+	for _, server := range opennicServers {
+		took, err := benchmarkDNSServer(server)
+		if err != nil {
+			log.Debugf("DNS server %s failed benchmark: %s", server, err)
+			continue
+		}
+
+		log.Debugf("DNS server %s answered in %s", server, took)
+		if best == "" || took < bestDuration {
+			best = server
+			bestDuration = took
+		}
+	}
+
+	dnsBenchmarkMu.Lock()
+	defer dnsBenchmarkMu.Unlock()
+
+	// Benchmark could be stopped while we were querying servers
+	select {
+	case <-closer:
+		return
+	default:
+	}
+
+	if best == "" {
+		log.Warningf("None of DNS servers answered, keeping previous selection: %s", dnsBestServer)
+		return
+	}
+
+	if best != dnsBestServer {
+		log.Infof("Selected fastest DNS server: %s (%s)", best, bestDuration)
+	}
+	dnsBestServer = best
+	dnsBestResolver = dns_resolver.New([]string{best})
+}
+
+// benchmarkDNSServer returns the time of the first successful lookup of benchmark hosts.
+func benchmarkDNSServer(server string) (took time.Duration, err error) {
+	r := dns_resolver.New([]string{server})
+	r.RetryTimes = 1
+
+	for _, host := range opennicBenchmarkHosts {
+		started := time.Now()
+		if _, err = r.LookupHost(host); err == nil {
+			return time.Since(started), nil
+		}
+	}
+	return
+}
@@ -102,7 +102,9 @@ func GetMovies(tmdbIds []int, language string) Movies {
 	for i, tmdbID := range tmdbIds {
 		go func(i int, tmdbId int) {
 			defer wg.Done()
-			movies[i] = GetMovie(tmdbId, language)
+			prefetch(func() {
+				movies[i] = GetMovie(tmdbId, language)
+			})
 		}(i, tmdbID)
 	}
 	wg.Wait()
@@ -299,7 +301,9 @@ func listMovies(endpoint string, cacheKey string, params napping.Params, page in
 
 					go func(rindex int, tmdbId int) {
 						defer wgItems.Done()
-						movies[rindex] = GetMovie(tmdbId, params["language"])
+						prefetch(func() {
+							movies[rindex] = GetMovie(tmdbId, params["language"])
+						})
 					}(rindex, movie.ID)
 				}
 				wgItems.Wait()
@@ -161,7 +161,9 @@ func GetShows(showIds []int, language string) Shows {
 	for i, showID := range showIds {
 		go func(i int, showId int) {
 			defer wg.Done()
-			shows[i] = GetShow(showId, language)
+			prefetch(func() {
+				shows[i] = GetShow(showId, language)
+			})
 		}(i, showID)
 	}
 	wg.Wait()
@@ -263,7 +265,9 @@ func listShows(endpoint string, cacheKey string, params napping.Params, page int
 
 					go func(rindex int, tmdbId int) {
 						defer wgItems.Done()
-						shows[rindex] = GetShow(tmdbId, params["language"])
+						prefetch(func() {
+							shows[rindex] = GetShow(tmdbId, params["language"])
+						})
 					}(rindex, show.ID)
 				}
 				wgItems.Wait()
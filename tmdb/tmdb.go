@@ -5,6 +5,7 @@ import (
 	"math/rand"
 	"net/url"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/elgatito/elementum/cache"
@@ -406,6 +407,45 @@ var (
 
 var rl = util.NewRateLimiter(burstRate, burstTime, simultaneousConnections)
 
+var (
+	prefetchMu    sync.Mutex
+	prefetchSlots chan bool
+)
+
+// prefetch runs metadata fetch function, limiting the number of simultaneous prefetches
+// to configured value, on top of the common TMDB rate limiter.
+func prefetch(f func()) {
+	slots := getPrefetchSlots()
+
+	select {
+	case slots <- true:
+	default:
+		log.Debugf("Metadata prefetch limit of %d reached, queueing request", cap(slots))
+		slots <- true
+	}
+	defer func() {
+		<-slots
+	}()
+
+	f()
+}
+
+func getPrefetchSlots() chan bool {
+	prefetchMu.Lock()
+	defer prefetchMu.Unlock()
+
+	limit := config.Get().MetadataPrefetchConcurrency
+	if limit <= 0 || limit > simultaneousConnections {
+		limit = simultaneousConnections
+	}
+
+	// Running prefetches keep using old channel, if limit was changed
+	if prefetchSlots == nil || cap(prefetchSlots) != limit {
+		prefetchSlots = make(chan bool, limit)
+	}
+	return prefetchSlots
+}
+
 // CheckAPIKey ...
 func CheckAPIKey() {
 	log.Info("Checking TMDB API key...")
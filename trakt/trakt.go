@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -61,6 +62,8 @@ const (
 var (
 	// ErrLocked reflects Trakt account locked status
 	ErrLocked = errors.New("Account is locked")
+	// ErrNotAuthorized reflects missing or rejected Trakt authorization
+	ErrNotAuthorized = errors.New("Not authorized")
 )
 
 var rl = util.NewRateLimiter(burstRate, burstTime, simultaneousConnections)
@@ -648,8 +651,8 @@ func GetWithAuth(endPoint string, params url.Values) (resp *napping.Response, er
 		if err != nil {
 			return err
 		} else if resp.Status() == 401 {
-			err = fmt.Errorf("Trakt access token is not valid, please, re-authorize Trakt")
-			log.Warningf("Request: %s, Error: %s", endPoint, err)
+			err = ErrNotAuthorized
+			log.Warningf("Request: %s, Error: Trakt access token is not valid, please, re-authorize Trakt", endPoint)
 			xbmc.Notify("Elementum", "LOCALIZE[30576]", config.AddonIcon())
 			return err
 		} else if resp.Status() == 429 {
@@ -1322,7 +1325,7 @@ func Scrobble(action string, contentType string, tmdbID int, watched float64, ru
 // GetLastActivities ...
 func GetLastActivities() (a *UserActivities, err error) {
 	if err := Authorized(); err != nil {
-		return nil, fmt.Errorf("Not authorized")
+		return nil, ErrNotAuthorized
 	}
 
 	endPoint := "sync/last_activities"
@@ -1334,6 +1337,8 @@ func GetLastActivities() (a *UserActivities, err error) {
 		return nil, err
 	} else if resp.Status() == 423 {
 		return nil, ErrLocked
+	} else if resp.Status() == 403 {
+		return nil, ErrNotAuthorized
 	} else if resp.Status() != 200 {
 		return nil, fmt.Errorf("Bad status getting Trakt activities: %d", resp.Status())
 	}
@@ -1347,6 +1352,17 @@ func GetLastActivities() (a *UserActivities, err error) {
 	return
 }
 
+// ShouldRetryTraktSync returns whether failed sync should be retried later,
+// which is true for network failures, but not for authorization or account errors.
+func ShouldRetryTraktSync(err error) bool {
+	if err == nil || err == ErrLocked || err == ErrNotAuthorized {
+		return false
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
 // DiffWatchedShows ...
 func DiffWatchedShows(current, previous []*WatchedShow) (diff []*WatchedShow) {
 	if current == nil || previous == nil || len(previous) == 0 || len(current) == 0 {